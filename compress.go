@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// minCompressBytes is the smallest response body Compress will bother
+// compressing; anything shorter is written through as-is.
+const minCompressBytes = 1024
+
+// defaultCompressibleTypes are the Content-Type prefixes Compress
+// compresses when no types are given explicitly. Images and video are
+// deliberately absent: they're already compressed, and gzipping them
+// again just burns CPU for a larger result.
+var defaultCompressibleTypes = []string{
+	"text/",
+	"application/json",
+	"application/xml",
+	"application/javascript",
+}
+
+// bestEncoding returns the highest q-value encoding Compress knows how
+// to produce ("gzip" or "deflate") out of an Accept-Encoding header, or
+// "" if the client accepts neither.
+func bestEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, e := range parseAccept(header) {
+		if e.name != "gzip" && e.name != "deflate" {
+			continue
+		}
+		if e.q > bestQ {
+			best, bestQ = e.name, e.q
+		}
+	}
+	return best
+}
+
+// Compress returns a Middleware that gzip- or deflate-encodes the
+// response body, chosen from the request's Accept-Encoding, at the
+// given compression level (see compress/gzip's level constants).
+// Responses are only compressed once they reach minCompressBytes (or
+// the handler calls Flush, which forces the decision early), and
+// only when their Content-Type matches one of types (defaultCompressibleTypes
+// if none are given) - this keeps small bodies and already-compressed
+// media (images, video) untouched.
+func Compress(level int, types ...string) Middleware {
+	if len(types) == 0 {
+		types = defaultCompressibleTypes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			encoding := bestEncoding(req.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, level: level, types: types}
+			next.ServeHTTP(cw, req)
+			cw.Close()
+		})
+	}
+}
+
+// compressWriter buffers a response until it can decide whether to
+// compress it: it needs to see the handler's Content-Type header and
+// enough bytes to clear minCompressBytes before committing either way.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	level       int
+	types       []string
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	passthrough bool
+	enc         io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	switch {
+	case w.passthrough:
+		return w.ResponseWriter.Write(b)
+	case w.enc != nil:
+		return w.enc.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= minCompressBytes {
+		w.commit()
+	}
+	return len(b), nil
+}
+
+// commit decides, the first time it's needed, whether the buffered body
+// should be compressed, then flushes headers and whatever was buffered
+// so far through the chosen path.
+func (w *compressWriter) commit() {
+	if !w.shouldCompress() {
+		w.passthrough = true
+		w.flushHeader()
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Encoding", w.encoding)
+	h.Add("Vary", "Accept-Encoding")
+	h.Del("Content-Length")
+	w.flushHeader()
+
+	if w.encoding == "gzip" {
+		gw, _ := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		w.enc = gw
+	} else {
+		fw, _ := flate.NewWriter(w.ResponseWriter, w.level)
+		w.enc = fw
+	}
+	w.enc.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+func (w *compressWriter) shouldCompress() bool {
+	ct := w.Header().Get("Content-Type")
+	for _, t := range w.types {
+		if strings.HasPrefix(ct, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *compressWriter) flushHeader() {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Close flushes anything still buffered (a body that never reached
+// minCompressBytes is written through uncompressed) and closes the
+// compressor, if one was committed to.
+func (w *compressWriter) Close() error {
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+	if w.passthrough {
+		return nil
+	}
+	w.passthrough = true
+	w.flushHeader()
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	return err
+}
+
+// Flush lets a compressWriter still satisfy http.Flusher, flushing any
+// in-flight compressor before the underlying writer, so SSE handlers
+// keep working through Compress. A body shorter than minCompressBytes
+// is still buffered awaiting that threshold when Flush is called; an
+// explicit Flush forces the compress-or-not decision immediately so
+// those bytes reach the client instead of sitting in the buffer.
+func (w *compressWriter) Flush() {
+	if w.enc == nil && !w.passthrough {
+		w.commit()
+	}
+
+	switch e := w.enc.(type) {
+	case *gzip.Writer:
+		e.Flush()
+	case *flate.Writer:
+		e.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets a compressWriter still satisfy http.Hijacker, so
+// websocket upgrades keep working through Compress.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("compress: underlying ResponseWriter does not support Hijack")
+	}
+	return hj.Hijack()
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"compress/gzip"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompressWriterFlushDrainsSmallBuffer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	cw := &compressWriter{ResponseWriter: rec, encoding: "gzip", level: gzip.DefaultCompression, types: defaultCompressibleTypes}
+
+	cw.Header().Set("Content-Type", "text/event-stream")
+	cw.Write([]byte("data: ping\n\n")) // 12 bytes, well under minCompressBytes
+
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected nothing written to the underlying writer before Flush, got %d bytes", rec.Body.Len())
+	}
+
+	cw.Flush()
+
+	if rec.Body.Len() == 0 {
+		t.Fatal("expected Flush to drain the buffered bytes to the underlying writer")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorHandler renders an error produced by the mux itself, or passed to
+// WriteError by a handler, onto the response. Register a custom one with
+// SetErrorHandler to emit RFC 7807 application/problem+json, plain text,
+// protobuf, or whatever envelope the API needs.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// MethodNotAllowedError is the error the mux produces when the request
+// path matched a registered route, but not for Method.
+type MethodNotAllowedError struct {
+	Method string
+	Allow  []string
+}
+
+func (e *MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("%s not allowed, allowed methods: %s", e.Method, strings.Join(e.Allow, ", "))
+}
+
+// NotFoundError is the error the mux produces when no registered route,
+// under any method, matches Path.
+type NotFoundError struct {
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no route matches %s", e.Path)
+}
+
+// defaultErrorHandler reproduces the mux's original behaviour: a JSON
+// body shaped like ErrStruct, with the "Allow" header set for a
+// MethodNotAllowedError, and a generic 500 for anything else.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var notAllowed *MethodNotAllowedError
+	var notFound *NotFoundError
+
+	switch {
+	case errors.As(err, &notAllowed):
+		w.Header().Set("Allow", strings.Join(notAllowed.Allow, ", "))
+		httpError(http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed on %s", notAllowed.Method, r.URL.Path), w)
+	case errors.As(err, &notFound):
+		httpError(http.StatusNotFound, fmt.Sprint("missing"), w)
+	default:
+		httpError(http.StatusInternalServerError, err.Error(), w)
+	}
+}
+
+// SetErrorHandler replaces the mux's ErrorHandler. It's inherited by any
+// sub-mux created with Group afterwards.
+func (m *MyMux) SetErrorHandler(eh ErrorHandler) {
+	m.errorHandler = eh
+}
+
+// WriteError renders err through the mux's ErrorHandler (or the default
+// one, if none was set), so user handlers can delegate to the same
+// pipeline the mux uses internally for 404s and 405s.
+func (m MyMux) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	eh := m.errorHandler
+	if eh == nil {
+		eh = defaultErrorHandler
+	}
+	eh(w, r, err)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Logger is a minimal structured logging interface, small enough that a
+// caller can drop in zap, zerolog, or whatever else without touching the
+// mux.
+type Logger interface {
+	Info(msg string, kv ...any)
+	Error(msg string, kv ...any)
+	With(k string, v any) Logger
+}
+
+// loggerCtxKey is the context key under which a request-scoped Logger is
+// stored by RequestLogger.
+type loggerCtxKey struct{}
+
+// LoggerFromContext returns the Logger stashed by RequestLogger, or a
+// stdlibLogger writing to log.Default() if none was stashed.
+func LoggerFromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+		return l
+	}
+	return NewStdLogger(log.Default())
+}
+
+// stdlibLogger is the default Logger, backed by the standard library's
+// log.Logger, so that users with no logging dependency of their own
+// still get sensible output.
+type stdlibLogger struct {
+	base   *log.Logger
+	fields []any
+}
+
+// NewStdLogger returns a Logger that writes to base.
+func NewStdLogger(base *log.Logger) Logger {
+	return &stdlibLogger{base: base}
+}
+
+func (l *stdlibLogger) Info(msg string, kv ...any)  { l.log("INFO", msg, kv...) }
+func (l *stdlibLogger) Error(msg string, kv ...any) { l.log("ERROR", msg, kv...) }
+
+func (l *stdlibLogger) With(k string, v any) Logger {
+	return &stdlibLogger{base: l.base, fields: append(append([]any(nil), l.fields...), k, v)}
+}
+
+func (l *stdlibLogger) log(level, msg string, kv ...any) {
+	all := append(append([]any(nil), l.fields...), kv...)
+	var b strings.Builder
+	for i := 0; i+1 < len(all); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", all[i], all[i+1])
+	}
+	l.base.Printf("%s %s%s", level, msg, b.String())
+}
+
+// statusWriter wraps http.ResponseWriter to capture the status code and
+// byte count written, so RequestLogger can log them after the handler
+// returns.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Flush lets a statusWriter still satisfy http.Flusher when the
+// underlying ResponseWriter does, so SSE handlers keep working.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets a statusWriter still satisfy http.Hijacker when the
+// underlying ResponseWriter does, so websocket upgrades keep working
+// through RequestLogger.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("logger: underlying ResponseWriter does not support Hijack")
+	}
+	return hj.Hijack()
+}
+
+// newRequestID returns a random hex-encoded request identifier.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestLogger returns a Middleware that assigns each request a random
+// ID (echoed back as X-Request-ID), stashes a Logger carrying that ID
+// plus the method and path on the request context (read back with
+// LoggerFromContext), and logs status, bytes written, and duration once
+// the handler returns. If base is nil, it falls back to a stdlibLogger.
+func RequestLogger(base Logger) Middleware {
+	if base == nil {
+		base = NewStdLogger(log.Default())
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id := newRequestID()
+			w.Header().Set("X-Request-ID", id)
+
+			logger := base.With("req_id", id).With("method", req.Method).With("path", req.URL.Path)
+			req = req.WithContext(context.WithValue(req.Context(), loggerCtxKey{}, logger))
+
+			sw := &statusWriter{ResponseWriter: w}
+			start := time.Now()
+			next.ServeHTTP(sw, req)
+
+			status := sw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			logger.Info("request completed", "status", status, "bytes", sw.bytes, "duration", time.Since(start))
+		})
+	}
+}
+
+// Recoverer returns a Middleware that recovers panics, logs the stack
+// trace via the context logger, and renders a 500 through m.WriteError,
+// so it always uses whatever ErrorHandler m carries at the time of the
+// panic, even if SetErrorHandler is called after Recoverer is wired up.
+func Recoverer(m *MyMux) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					LoggerFromContext(req.Context()).Error("panic recovered", "panic", rec, "stack", string(debug.Stack()))
+					m.WriteError(w, req, fmt.Errorf("internal server error: %v", rec))
+				}
+			}()
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// hijackableRecorder is an httptest.ResponseRecorder that also satisfies
+// http.Hijacker, so tests can prove a wrapper still passes Hijack through.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+func TestStatusWriterHijackPassesThrough(t *testing.T) {
+	rec := hijackableRecorder{httptest.NewRecorder()}
+	if _, ok := any(rec).(http.Hijacker); !ok {
+		t.Fatal("test setup broken: hijackableRecorder must satisfy http.Hijacker")
+	}
+
+	sw := &statusWriter{ResponseWriter: rec}
+	hj, ok := any(sw).(http.Hijacker)
+	if !ok {
+		t.Fatal("expected statusWriter wrapping a Hijacker to satisfy http.Hijacker")
+	}
+	if _, _, err := hj.Hijack(); err != nil {
+		t.Fatalf("Hijack() returned unexpected error: %v", err)
+	}
+}
+
+func TestRecovererUsesLiveErrorHandler(t *testing.T) {
+	m := &MyMux{}
+	m.Use(Recoverer(m))
+	m.GET("/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	var called bool
+	m.SetErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		called = true
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	if !called {
+		t.Fatal("expected Recoverer to use the ErrorHandler set via SetErrorHandler after Use(Recoverer(m))")
+	}
+	if w.Code != http.StatusTeapot {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusTeapot)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Middleware wraps an http.Handler to produce a new http.Handler, e.g. to
+// log requests, inject context values, or short-circuit a response.
+type Middleware func(http.Handler) http.Handler
+
+// chain folds mws right-to-left around h, so that mws[0] ends up as the
+// outermost handler and mws[len(mws)-1] as the innermost, closest to h.
+func chain(mws []Middleware, h http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// JSONMiddleware sets the response Content-Type to application/json.
+// It behaves like the original jsonDecorator, kept as a Middleware so it
+// can be composed with Use()/Group().
+func JSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		next.ServeHTTP(w, req)
+	})
+}
+
+// LogMiddleware prints the request to stdout before calling the next
+// handler. It behaves like the original printDecorator, kept as a
+// Middleware so it can be composed with Use()/Group().
+func LogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Printf("%s \"%s\" -> %s\n", req.Method, req.URL.Path, req.UserAgent())
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddlewareChainRunsOnNotFoundAndMethodNotAllowed(t *testing.T) {
+	m := &MyMux{}
+	var ran bool
+	m.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ran = true
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.GET("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	ran = false
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if !ran {
+		t.Error("expected Use()'d middleware to run for a 404 response")
+	}
+
+	ran = false
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	if !ran {
+		t.Error("expected Use()'d middleware to run for a 405 response")
+	}
+}
+
+func TestUseOrdersMiddlewareFirstRegisteredIsOutermost(t *testing.T) {
+	m := &MyMux{}
+	var trace []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				trace = append(trace, name+":before")
+				next.ServeHTTP(w, r)
+				trace = append(trace, name+":after")
+			})
+		}
+	}
+	m.Use(record("outer"))
+	m.Use(record("inner"))
+	m.GET("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	want := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(trace) != len(want) {
+		t.Fatalf("got trace %v, want %v", trace, want)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("got trace %v, want %v", trace, want)
+		}
+	}
+}
+
+func TestGroupMiddlewareDoesNotLeakToParent(t *testing.T) {
+	m := &MyMux{}
+	var parentRan, groupRan bool
+	m.GET("/parent", func(w http.ResponseWriter, r *http.Request) { parentRan = true })
+
+	m.Group("/api", func(g *MyMux) {
+		g.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				groupRan = true
+				next.ServeHTTP(w, r)
+			})
+		})
+		g.GET("/thing", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	parentRan, groupRan = false, false
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/parent", nil))
+	if !parentRan {
+		t.Fatal("expected the parent route's handler to run")
+	}
+	if groupRan {
+		t.Error("expected the group's Use()'d middleware not to run for a parent route")
+	}
+
+	parentRan, groupRan = false, false
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/thing", nil))
+	if !groupRan {
+		t.Error("expected the group's Use()'d middleware to run for the group's own route")
+	}
+}
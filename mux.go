@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// paramsCtxKey is the context key under which captured path parameters
+// are stored on the request context.
+type paramsCtxKey struct{}
+
+// routeParams holds the named path parameters captured while matching
+// a request against a registered route pattern.
+type routeParams map[string]string
+
+// nodeKind describes what kind of path segment a trieNode matches.
+type nodeKind int
+
+const (
+	nodeLiteral  nodeKind = iota // "/users"
+	nodeParam                    // "/{id}"
+	nodeCatchAll                 // "/*rest"
+)
+
+// trieNode is a single path segment in a method's routing trie.
+type trieNode struct {
+	kind     nodeKind
+	segment  string // literal text, or the param/catch-all name
+	handler  http.HandlerFunc
+	children []*trieNode
+}
+
+// findChild returns the existing child of the given kind that matches
+// segment, validating that a param/catch-all slot isn't redefined with
+// a conflicting name.
+func (n *trieNode) findChild(kind nodeKind, segment string) *trieNode {
+	for _, c := range n.children {
+		if c.kind != kind {
+			continue
+		}
+		switch kind {
+		case nodeLiteral:
+			if c.segment == segment {
+				return c
+			}
+		case nodeParam, nodeCatchAll:
+			if c.segment != segment {
+				panic(fmt.Sprintf("mux: conflicting route parameter names %q and %q at the same position", c.segment, segment))
+			}
+			return c
+		}
+	}
+	return nil
+}
+
+// splitPath turns "/users/{id}" into ["users", "{id}"], treating "/" as
+// the empty (root) segment list.
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// segmentKind classifies a single raw path segment.
+func segmentKind(seg string) (nodeKind, string) {
+	switch {
+	case strings.HasPrefix(seg, "*"):
+		return nodeCatchAll, seg[1:]
+	case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && len(seg) > 2:
+		return nodeParam, seg[1 : len(seg)-1]
+	default:
+		return nodeLiteral, seg
+	}
+}
+
+// insertRoute compiles pattern into root, creating trie nodes as needed,
+// and attaches h to the terminal node. It panics if pattern was already
+// registered, or if it conflicts with an existing parameter/catch-all name.
+func insertRoute(root *trieNode, pattern string, h http.HandlerFunc) {
+	node := root
+	segments := splitPath(pattern)
+	for i, seg := range segments {
+		if node.kind == nodeCatchAll {
+			panic(fmt.Sprintf("mux: pattern %q has segments after a catch-all", pattern))
+		}
+		kind, name := segmentKind(seg)
+		if kind == nodeCatchAll && i != len(segments)-1 {
+			panic(fmt.Sprintf("mux: catch-all %q must be the last segment in %q", seg, pattern))
+		}
+		child := node.findChild(kind, name)
+		if child == nil {
+			child = &trieNode{kind: kind, segment: name}
+			node.children = append(node.children, child)
+		}
+		node = child
+	}
+	if node.handler != nil {
+		panic(fmt.Sprintf("mux: handler already registered for pattern %q", pattern))
+	}
+	node.handler = h
+}
+
+// matchRoute walks root looking for a handler for segments, preferring
+// literal children over named children over a catch-all, and returns
+// any path parameters captured along the way.
+func matchRoute(node *trieNode, segments []string) (*trieNode, routeParams) {
+	if len(segments) == 0 {
+		if node.handler == nil {
+			return nil, nil
+		}
+		return node, nil
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	for _, kind := range []nodeKind{nodeLiteral, nodeParam, nodeCatchAll} {
+		for _, child := range node.children {
+			if child.kind != kind {
+				continue
+			}
+			switch kind {
+			case nodeLiteral:
+				if child.segment != seg {
+					continue
+				}
+				if n, p := matchRoute(child, rest); n != nil {
+					return n, p
+				}
+			case nodeParam:
+				n, p := matchRoute(child, rest)
+				if n == nil {
+					continue
+				}
+				if p == nil {
+					p = routeParams{}
+				}
+				p[child.segment] = seg
+				return n, p
+			case nodeCatchAll:
+				if child.handler == nil {
+					continue
+				}
+				return child, routeParams{child.segment: strings.Join(segments, "/")}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// methodTrieMap looks up a method's routing trie by HTTP method.
+type methodTrieMap map[string]*trieNode
+
+// MyMux is our own special muxer.
+// It keeps one routing trie per HTTP method, so path parameters such as
+// "/users/{id}" and catch-alls such as "/files/*rest" are matched in
+// addition to plain literal paths. The MyMux that Group passes to its fn
+// shares its parent's trees but carries its own prefix and middleware chain.
+type MyMux struct {
+	trees        methodTrieMap
+	prefix       string
+	middleware   []Middleware
+	errorHandler ErrorHandler
+	encoders     []Encoder
+	routes       *[]compiledRoute
+}
+
+// ServeHTTP basic HTTP Handler
+// Register the handler for the proper path and method
+func (m MyMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	segments := splitPath(req.URL.Path)
+
+	if root := m.trees[req.Method]; root != nil {
+		if node, params := matchRoute(root, segments); node != nil {
+			if len(params) > 0 {
+				req = req.WithContext(context.WithValue(req.Context(), paramsCtxKey{}, params))
+			}
+			node.handler(w, req)
+			return
+		}
+	}
+
+	var err error
+	if allowed := m.allowedMethods(req.Method, segments); len(allowed) > 0 {
+		err = &MethodNotAllowedError{Method: req.Method, Allow: allowed}
+	} else {
+		err = &NotFoundError{Path: req.URL.Path}
+	}
+
+	// Route 404/405s through the middleware chain too, same as any
+	// registered handler, so Use()'d middleware like RequestLogger and
+	// Recoverer still run for unmatched paths.
+	notFound := chain(m.middleware, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		m.WriteError(w, req, err)
+	}))
+	notFound.ServeHTTP(w, req)
+}
+
+// allowedMethods returns, sorted, every HTTP method other than except
+// that has a route matching segments, so ServeHTTP can tell a 404 from
+// a 405 and populate the Allow header.
+func (m MyMux) allowedMethods(except string, segments []string) []string {
+	var allowed []string
+	for method, root := range m.trees {
+		if method == except {
+			continue
+		}
+		if node, _ := matchRoute(root, segments); node != nil {
+			allowed = append(allowed, method)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// URLParam returns the value of a named path parameter (e.g. "id" for a
+// route registered as "/users/{id}") captured while matching r, or ""
+// if it wasn't set.
+func (MyMux) URLParam(r *http.Request, key string) string {
+	params, _ := r.Context().Value(paramsCtxKey{}).(routeParams)
+	return params[key]
+}
+
+// RegisterHandler registers new handlers, running them through any
+// middleware accumulated via Use() on this mux (or group).
+func (m *MyMux) RegisterHandler(method string, path string, h http.HandlerFunc) {
+	m.registerHandler(method, path, h)
+}
+
+// registerHandler compiles method+path into the trie, wrapping h with
+// this mux's middleware chain plus any extra middleware supplied by the
+// caller (e.g. the JSON/log decorators applied by Register).
+func (m *MyMux) registerHandler(method string, path string, h http.HandlerFunc, extra ...Middleware) {
+	if m.trees == nil {
+		m.trees = make(methodTrieMap)
+	}
+	root := m.trees[method]
+	if root == nil {
+		root = &trieNode{kind: nodeLiteral}
+		m.trees[method] = root
+	}
+
+	mws := make([]Middleware, 0, len(m.middleware)+len(extra))
+	mws = append(mws, m.middleware...)
+	mws = append(mws, extra...)
+	wrapped := chain(mws, http.HandlerFunc(h))
+
+	insertRoute(root, m.prefix+path, wrapped.ServeHTTP)
+}
+
+// Use appends middleware to this mux's chain. Middleware is folded
+// right-to-left around every handler registered afterwards, so the
+// first middleware passed to Use is the outermost one.
+func (m *MyMux) Use(mw ...Middleware) {
+	m.middleware = append(m.middleware, mw...)
+}
+
+// Group calls fn with a sub-mux rooted at prefix that shares this mux's
+// routing trees and inherits its middleware chain. Middleware added
+// via Use() inside fn only applies to routes registered within the
+// group, not to the parent mux. The sub-mux is only handed to fn;
+// Group itself returns nothing.
+func (m *MyMux) Group(prefix string, fn func(*MyMux)) {
+	if m.trees == nil {
+		m.trees = make(methodTrieMap)
+	}
+	if m.routes == nil {
+		m.routes = new([]compiledRoute)
+	}
+	sub := &MyMux{
+		trees:        m.trees,
+		prefix:       m.prefix + prefix,
+		middleware:   append([]Middleware(nil), m.middleware...),
+		errorHandler: m.errorHandler,
+		encoders:     append([]Encoder(nil), m.encoders...),
+		routes:       m.routes,
+	}
+	fn(sub)
+}
+
+// Register mounts an endpoint at path. edph must implement RouteProvider,
+// EndpointMethods, or both:
+//
+//   - If it implements RouteProvider, its declared Routes are mounted via
+//     reflection and become discoverable through ServeOpenAPI. A
+//     RouteProvider does NOT also need to implement EndpointMethods -
+//     there's no need to embed EndpointHandler just to satisfy it with
+//     dead no-op stubs.
+//   - Otherwise it must implement EndpointMethods, and its GET/PUT/POST/
+//     DELETE/OPTIONS methods are mounted directly, decorated per
+//     DecorateJSON/DecorateLOG.
+//
+// Register panics if edph implements neither.
+func (m *MyMux) Register(path string, edph any) {
+	if rp, ok := edph.(RouteProvider); ok {
+		m.registerRoutes(path, rp, rp.Routes())
+		return
+	}
+
+	em, ok := edph.(EndpointMethods)
+	if !ok {
+		panic(fmt.Sprintf("mux: %T implements neither RouteProvider nor EndpointMethods", edph))
+	}
+
+	var extra []Middleware
+	if em.DecorateLOG() {
+		extra = append(extra, LogMiddleware)
+	}
+	if em.DecorateJSON() {
+		extra = append(extra, JSONMiddleware)
+	}
+
+	methods := map[string]http.HandlerFunc{
+		http.MethodGet:     em.GET,
+		http.MethodPut:     em.PUT,
+		http.MethodPost:    em.POST,
+		http.MethodDelete:  em.DELETE,
+		http.MethodOptions: em.OPTIONS,
+	}
+	for method, h := range methods {
+		m.registerHandler(method, path, h, extra...)
+	}
+}
+
+// GET alias for RegisterHandler w/ GET argument
+func (m *MyMux) GET(path string, h http.HandlerFunc) {
+	m.RegisterHandler(http.MethodGet, path, h)
+}
+
+// PUT alias for RegisterHandler w/ PUT argument
+func (m *MyMux) PUT(path string, h http.HandlerFunc) {
+	m.RegisterHandler(http.MethodPut, path, h)
+}
+
+// POST alias for RegisterHandler w/ POST argument
+func (m *MyMux) POST(path string, h http.HandlerFunc) {
+	m.RegisterHandler(http.MethodPost, path, h)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTriePrecedence(t *testing.T) {
+	m := &MyMux{}
+	var hit string
+
+	m.GET("/users/static", func(w http.ResponseWriter, r *http.Request) { hit = "literal" })
+	m.GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) { hit = "param:" + m.URLParam(r, "id") })
+	m.GET("/users/*rest", func(w http.ResponseWriter, r *http.Request) { hit = "catchall:" + m.URLParam(r, "rest") })
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/users/static", "literal"},   // literal beats param and catch-all
+		{"/users/123", "param:123"},    // param beats catch-all
+		{"/users/a/b", "catchall:a/b"}, // only catch-all matches multi-segment tails
+	}
+
+	for _, c := range cases {
+		hit = ""
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, req)
+		if hit != c.want {
+			t.Errorf("path %q: got hit %q, want %q", c.path, hit, c.want)
+		}
+	}
+}
+
+func TestRegisterHandlerPanicsOnDuplicatePattern(t *testing.T) {
+	m := &MyMux{}
+	m.GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic when the same pattern is registered twice")
+		}
+	}()
+	m.GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func TestRegisterHandlerPanicsOnConflictingParamNames(t *testing.T) {
+	m := &MyMux{}
+	m.GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic when two patterns disagree on a param name at the same position")
+		}
+	}()
+	m.GET("/users/{slug}", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func TestTrailingSlashIsNormalized(t *testing.T) {
+	m := &MyMux{}
+	var hit bool
+	m.GET("/users", func(w http.ResponseWriter, r *http.Request) { hit = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/users/", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	if !hit {
+		t.Fatal("expected /users/ to match a route registered as /users")
+	}
+}
+
+func TestMethodNotAllowedSetsAllowHeader(t *testing.T) {
+	m := &MyMux{}
+	m.GET("/users", func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	var body ErrStruct
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if body.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got body code %d, want %d", body.Code, http.StatusMethodNotAllowed)
+	}
+	if got := w.Header().Get("Allow"); got != http.MethodGet {
+		t.Fatalf("Allow header = %q, want %q", got, http.MethodGet)
+	}
+}
+
+func TestNotFoundForUnregisteredPath(t *testing.T) {
+	m := &MyMux{}
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	var body ErrStruct
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if body.Code != http.StatusNotFound {
+		t.Fatalf("got body code %d, want %d", body.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Route declares one HTTP route a declarative endpoint exposes: its
+// method and path (relative to the prefix it's registered under), a
+// human-readable summary for docs, the request/response shapes used to
+// bind incoming requests and derive the OpenAPI schema, and the name of
+// the auth scheme it requires, if any.
+type Route struct {
+	Method   string
+	Path     string
+	Summary  string
+	Request  any
+	Response any
+	Auth     string
+}
+
+// RouteProvider is implemented by endpoints that declare their routes,
+// schemas, and auth requirements declaratively instead of hand-rolling
+// EndpointMethods. MyMux.Register mounts these with reflection, and
+// MyMux.ServeOpenAPI documents them.
+type RouteProvider interface {
+	Routes() []Route
+}
+
+// compiledRoute is a Route with its full (prefix-joined) path recorded,
+// ready to be both mounted and documented by ServeOpenAPI.
+type compiledRoute struct {
+	Route
+	FullPath string
+}
+
+// declarableMethods are the HTTP methods a Route is allowed to declare.
+var declarableMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPut:     true,
+	http.MethodPost:    true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodPatch:   true,
+}
+
+// errorType is the reflect.Type of the built-in error interface, used
+// by validateRouteSignature to check a route method's last return value.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// registerRoutes mounts each of routes under prefix. Every route's
+// Method must name one of declarableMethods and match a method of the
+// same name on edph; that method's signature is validated once here
+// (consistent with how Register validates and compiles patterns once
+// at registration time) and must be func(Request) (Response, error),
+// func(Request) error, func() (Response, error), or func() error,
+// matching whether the route declares a Request and/or Response.
+func (m *MyMux) registerRoutes(prefix string, edph RouteProvider, routes []Route) {
+	if m.routes == nil {
+		m.routes = new([]compiledRoute)
+	}
+
+	v := reflect.ValueOf(edph)
+	for _, route := range routes {
+		if !declarableMethods[route.Method] {
+			panic(fmt.Sprintf("mux: route %s %s declares an unsupported method", route.Method, route.Path))
+		}
+		fn := v.MethodByName(route.Method)
+		if !fn.IsValid() {
+			panic(fmt.Sprintf("mux: %T declares a %s route but has no %s method", edph, route.Method, route.Method))
+		}
+		validateRouteSignature(edph, route, fn)
+
+		full := prefix + route.Path
+		*m.routes = append(*m.routes, compiledRoute{Route: route, FullPath: full})
+		m.registerHandler(route.Method, full, m.bindRoute(fn, route))
+	}
+}
+
+// validateRouteSignature panics unless fn's argument and return types
+// match route's declared Request/Response: one argument of type
+// route.Request if it's non-nil (none otherwise), and a final error
+// return preceded by a route.Response-typed return if it's non-nil (no
+// other return otherwise).
+func validateRouteSignature(edph any, route Route, fn reflect.Value) {
+	t := fn.Type()
+
+	wantIn := 0
+	if route.Request != nil {
+		wantIn = 1
+	}
+	if t.NumIn() != wantIn {
+		panic(fmt.Sprintf("mux: %T.%s takes %d argument(s), want %d", edph, route.Method, t.NumIn(), wantIn))
+	}
+	if wantIn == 1 && t.In(0) != reflect.TypeOf(route.Request) {
+		panic(fmt.Sprintf("mux: %T.%s takes %s, want %s", edph, route.Method, t.In(0), reflect.TypeOf(route.Request)))
+	}
+
+	wantOut := 1
+	if route.Response != nil {
+		wantOut = 2
+	}
+	if t.NumOut() != wantOut {
+		panic(fmt.Sprintf("mux: %T.%s returns %d value(s), want %d", edph, route.Method, t.NumOut(), wantOut))
+	}
+	if !t.Out(wantOut - 1).Implements(errorType) {
+		panic(fmt.Sprintf("mux: %T.%s's last return value must implement error", edph, route.Method))
+	}
+	if route.Response != nil && t.Out(0) != reflect.TypeOf(route.Response) {
+		panic(fmt.Sprintf("mux: %T.%s returns %s, want %s", edph, route.Method, t.Out(0), reflect.TypeOf(route.Response)))
+	}
+}
+
+// bindRoute returns the http.HandlerFunc that decodes a request into
+// route.Request (a JSON body, if any, then path params, which overwrite
+// body fields of the same name), invokes
+// fn with it, and renders whatever fn returns through content
+// negotiation - or, if route.Response is nil, writes a bare 204 once fn
+// returns a nil error.
+func (m *MyMux) bindRoute(fn reflect.Value, route Route) http.HandlerFunc {
+	reqType := reflect.TypeOf(route.Request)
+	hasResponse := route.Response != nil
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var args []reflect.Value
+
+		if reqType != nil {
+			reqPtr := reflect.New(reqType)
+			if r.Body != nil && r.ContentLength != 0 {
+				dec := json.NewDecoder(r.Body)
+				dec.DisallowUnknownFields()
+				if err := dec.Decode(reqPtr.Interface()); err != nil && err != io.EOF {
+					m.WriteError(w, r, fmt.Errorf("decoding request body: %w", err))
+					return
+				}
+			}
+			bindPathParams(m, r, reqPtr.Elem())
+			args = append(args, reqPtr.Elem())
+		}
+
+		results := fn.Call(args)
+		errVal := results[len(results)-1]
+		if !errVal.IsNil() {
+			m.WriteError(w, r, errVal.Interface().(error))
+			return
+		}
+		if !hasResponse {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		m.Render(w, r, http.StatusOK, results[0].Interface())
+	}
+}
+
+// bindPathParams copies any trie-captured path parameter whose name
+// matches a Request field's "json" tag (or its lowercased field name)
+// into that field.
+func bindPathParams(m *MyMux, r *http.Request, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = strings.ToLower(field.Name)
+		}
+		if val := m.URLParam(r, name); val != "" {
+			setStringField(v.Field(i), val)
+		}
+	}
+}
+
+// setStringField assigns the string path-param value val to f,
+// converting it for int-kinded fields.
+func setStringField(f reflect.Value, val string) {
+	if !f.CanSet() {
+		return
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			f.SetInt(n)
+		}
+	}
+}
+
+// ServeOpenAPI mounts a GET handler at path that serves an OpenAPI 3.0
+// document describing every route registered through a RouteProvider
+// endpoint, plus a minimal embedded Swagger UI at path+"/ui".
+func (m *MyMux) ServeOpenAPI(path string) {
+	m.GET(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(m.openAPIDocument())
+	})
+	m.GET(path+"/ui", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, swaggerUIHTML(path))
+	})
+}
+
+// openAPIDocument walks m.routes and builds a map matching the OpenAPI
+// 3.0 schema: paths, operations, and component schemas derived from
+// each route's Request/Response via reflection.
+func (m *MyMux) openAPIDocument() map[string]any {
+	var routes []compiledRoute
+	if m.routes != nil {
+		routes = append(routes, *m.routes...)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].FullPath < routes[j].FullPath })
+
+	paths := map[string]any{}
+	schemas := map[string]any{}
+
+	for _, route := range routes {
+		op := map[string]any{"summary": route.Summary}
+		if route.Auth != "" {
+			op["security"] = []map[string]any{{route.Auth: []string{}}}
+		}
+		if route.Request != nil {
+			name := schemaName(route.Request)
+			schemas[name] = schemaFor(route.Request)
+			op["requestBody"] = map[string]any{
+				"content": map[string]any{
+					"application/json": map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/" + name},
+					},
+				},
+			}
+		}
+		if route.Response != nil {
+			name := schemaName(route.Response)
+			schemas[name] = schemaFor(route.Response)
+			op["responses"] = map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": map[string]any{"$ref": "#/components/schemas/" + name},
+						},
+					},
+				},
+			}
+		}
+
+		p, _ := paths[route.FullPath].(map[string]any)
+		if p == nil {
+			p = map[string]any{}
+			paths[route.FullPath] = p
+		}
+		p[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi":    "3.0.0",
+		"info":       map[string]any{"title": "API", "version": "1.0.0"},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// schemaName returns the Go type name backing a route's Request or
+// Response value, used as its OpenAPI component name.
+func schemaName(v any) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// schemaFor builds a JSON Schema object for v's underlying struct type
+// from its fields' "json", "validate", and "doc" tags.
+func schemaFor(v any) map[string]any {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	props := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+		if name == "-" {
+			continue
+		}
+
+		prop := map[string]any{"type": jsonSchemaType(f.Type)}
+		if doc := f.Tag.Get("doc"); doc != "" {
+			prop["description"] = doc
+		}
+		if strings.Contains(f.Tag.Get("validate"), "required") {
+			required = append(required, name)
+		}
+		props[name] = prop
+	}
+
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonSchemaType maps a Go field type to its closest JSON Schema type.
+func jsonSchemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// swaggerUIHTML renders a minimal page that loads Swagger UI from a CDN
+// and points it at the OpenAPI document served alongside it.
+func swaggerUIHTML(specPath string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>API Docs</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist/swagger-ui-bundle.js"></script>
+<script>
+  window.onload = () => SwaggerUIBundle({ url: %q, dom_id: '#swagger-ui' })
+</script>
+</body>
+</html>
+`, specPath)
+}
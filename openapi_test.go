@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type pingRequest struct {
+	Name string `json:"name"`
+}
+
+type pingResponse struct {
+	Msg string `json:"msg"`
+}
+
+// pingEndpoint implements RouteProvider only - no EndpointMethods stubs.
+type pingEndpoint struct{}
+
+func (pingEndpoint) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Path: "/ping/{name}", Summary: "ping", Request: pingRequest{}, Response: pingResponse{}},
+	}
+}
+
+func (pingEndpoint) GET(req pingRequest) (pingResponse, error) {
+	return pingResponse{Msg: "hello " + req.Name}, nil
+}
+
+func TestRegisterAcceptsRouteProviderWithoutEndpointMethods(t *testing.T) {
+	m := &MyMux{}
+	m.Register("/api", pingEndpoint{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping/gopher", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	if w.Body.Len() == 0 {
+		t.Fatal("expected the route's typed GET method to render a response body")
+	}
+}
+
+type deleteRequest struct {
+	ID string `json:"id"`
+}
+
+// deleteEndpoint declares a route with no Response, the natural shape
+// for a method that only ever returns an error.
+type deleteEndpoint struct{}
+
+func (deleteEndpoint) Routes() []Route {
+	return []Route{
+		{Method: http.MethodDelete, Path: "/things/{id}", Summary: "delete a thing", Request: deleteRequest{}},
+	}
+}
+
+func (deleteEndpoint) DELETE(req deleteRequest) error { return nil }
+
+func TestRegisterRouteWithoutResponseReturns204(t *testing.T) {
+	m := &MyMux{}
+	m.Register("/api", deleteEndpoint{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/things/42", nil)
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+// badSignatureEndpoint declares a route with a Request, but its GET
+// method takes no arguments - a registration-time signature mismatch.
+type badSignatureEndpoint struct{}
+
+func (badSignatureEndpoint) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Path: "/bad", Summary: "bad", Request: pingRequest{}, Response: pingResponse{}},
+	}
+}
+
+func (badSignatureEndpoint) GET() (pingResponse, error) { return pingResponse{}, nil }
+
+func TestRegisterPanicsOnRouteSignatureMismatch(t *testing.T) {
+	m := &MyMux{}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Register to panic when a route declares a Request its bound method doesn't accept")
+		}
+	}()
+	m.Register("/api", badSignatureEndpoint{})
+}
+
+func TestRegisterPanicsOnNeitherInterface(t *testing.T) {
+	m := &MyMux{}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Register to panic for a value implementing neither RouteProvider nor EndpointMethods")
+		}
+	}()
+	m.Register("/nope", struct{}{})
+}
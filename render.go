@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoder serializes a value onto w and reports the content type it
+// produces, so MyMux.Render can negotiate the best one for a request's
+// Accept header.
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+// jsonEncoder is the built-in application/json Encoder.
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string             { return "application/json; charset=utf-8" }
+func (jsonEncoder) Encode(w io.Writer, v any) error { return json.NewEncoder(w).Encode(v) }
+
+// xmlEncoder is the built-in application/xml Encoder.
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string             { return "application/xml; charset=utf-8" }
+func (xmlEncoder) Encode(w io.Writer, v any) error { return xml.NewEncoder(w).Encode(v) }
+
+// defaultEncoders are used by Render when a mux has none registered.
+// JSON comes first, so it's the one picked for a wildcard Accept such
+// as "*/*".
+func defaultEncoders() []Encoder {
+	return []Encoder{jsonEncoder{}, xmlEncoder{}}
+}
+
+// RegisterEncoder adds encs to this mux's content negotiation table,
+// tried in registration order when one Accept entry matches several.
+func (m *MyMux) RegisterEncoder(encs ...Encoder) {
+	m.encoders = append(m.encoders, encs...)
+}
+
+// acceptEntry is one weighted entry out of a parsed Accept (or
+// Accept-Encoding) header.
+type acceptEntry struct {
+	name string
+	q    float64
+}
+
+// parseAccept splits a header like "application/json;q=0.9, text/*" into
+// weighted entries, sorted by descending q-value.
+func parseAccept(header string) []acceptEntry {
+	if header == "" {
+		return nil
+	}
+	parts := strings.Split(header, ",")
+	entries := make([]acceptEntry, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		name, params, _ := strings.Cut(p, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			param = strings.TrimSpace(param)
+			v, ok := strings.CutPrefix(param, "q=")
+			if !ok {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		entries = append(entries, acceptEntry{name: strings.TrimSpace(name), q: q})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+	return entries
+}
+
+// matchesMediaType reports whether pattern (e.g. "*/*", "application/*",
+// "application/json") matches candidate, ignoring any parameters on
+// candidate (such as "; charset=utf-8").
+func matchesMediaType(pattern, candidate string) bool {
+	candidate, _, _ = strings.Cut(candidate, ";")
+	candidate = strings.TrimSpace(candidate)
+	if pattern == "*/*" {
+		return true
+	}
+	pType, pSub, ok := strings.Cut(pattern, "/")
+	if !ok {
+		return false
+	}
+	cType, cSub, ok := strings.Cut(candidate, "/")
+	if !ok {
+		return false
+	}
+	return (pType == "*" || pType == cType) && (pSub == "*" || pSub == cSub)
+}
+
+// pickEncoder returns the highest-preference encoder in encs that
+// matches accept, or the built-in JSON encoder if nothing in accept
+// matches (or accept is empty), so Render always has a fallback.
+func pickEncoder(encs []Encoder, accept string) Encoder {
+	for _, entry := range parseAccept(accept) {
+		if entry.q <= 0 {
+			continue
+		}
+		for _, enc := range encs {
+			if matchesMediaType(entry.name, enc.ContentType()) {
+				return enc
+			}
+		}
+	}
+	return jsonEncoder{}
+}
+
+// Render picks the best Encoder registered on m for r's Accept header
+// (falling back to JSON if none were registered, or none match), writes
+// status and the matching Content-Type, and encodes v onto w.
+func (m MyMux) Render(w http.ResponseWriter, r *http.Request, status int, v any) error {
+	encs := m.encoders
+	if len(encs) == 0 {
+		encs = defaultEncoders()
+	}
+	enc := pickEncoder(encs, r.Header.Get("Accept"))
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	return enc.Encode(w, v)
+}
@@ -90,97 +90,6 @@ func httpError(code int, errString string, w http.ResponseWriter) {
 	}
 }
 
-// urlHandler lookups a http.HandlerFunc by HTTP Method
-type urlHandlerMap map[string]http.HandlerFunc
-
-// methodUrlHandler lookups a http.HandlerFunc by HTTP Method
-type methodURLHandlerMap map[string]urlHandlerMap
-
-// MyMux is our own special muxer
-// All it does is keep track of which endpoints and HTTP methods belong together.
-// Nothig fancy with path matching.
-type MyMux struct {
-	handlers methodURLHandlerMap
-}
-
-// ServeHTTP basic HTTP Handler
-// Register the handler for the proper path and method
-func (m MyMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	// Match Method to stored handlers
-	if urlH := m.handlers[req.URL.Path]; urlH != nil {
-		if h := urlH[req.Method]; h != nil {
-			h(w, req)
-		} else {
-			httpError(http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed on %s", req.Method, req.URL.Path), w)
-		}
-	} else {
-		httpError(http.StatusNotFound, fmt.Sprint("missing"), w)
-	}
-}
-
-// RegisterHandler registers new handlers
-func (m *MyMux) RegisterHandler(method string, path string, h http.HandlerFunc) {
-	if m.handlers == nil {
-		m.handlers = make(methodURLHandlerMap, 0)
-	}
-
-	if urlH := m.handlers[path]; urlH == nil {
-		m.handlers[path] = make(urlHandlerMap, 1)
-	}
-	m.handlers[path][method] = h
-
-}
-
-// Register takes an instance of a struct (or such) which implements the EndpointMethods interface.
-// Decorate the handlers properly.
-func (m *MyMux) Register(path string, edph EndpointMethods) {
-	if m.handlers == nil {
-		m.handlers = make(methodURLHandlerMap, 0)
-	}
-
-	if urlH := m.handlers[path]; urlH == nil {
-		m.handlers[path] = make(urlHandlerMap, 1)
-	}
-
-	m.handlers[path][http.MethodGet] = edph.GET
-	m.handlers[path][http.MethodPut] = edph.PUT
-	m.handlers[path][http.MethodPost] = edph.POST
-	m.handlers[path][http.MethodDelete] = edph.DELETE
-	m.handlers[path][http.MethodOptions] = edph.OPTIONS
-
-	if edph.DecorateJSON() {
-		m.handlers[path][http.MethodGet] = jsonDecorator(m.handlers[path][http.MethodGet])
-		m.handlers[path][http.MethodPut] = jsonDecorator(m.handlers[path][http.MethodPut])
-		m.handlers[path][http.MethodPost] = jsonDecorator(m.handlers[path][http.MethodPost])
-		m.handlers[path][http.MethodDelete] = jsonDecorator(m.handlers[path][http.MethodDelete])
-		m.handlers[path][http.MethodOptions] = jsonDecorator(m.handlers[path][http.MethodOptions])
-	}
-
-	if edph.DecorateLOG() {
-		m.handlers[path][http.MethodGet] = printDecorator(m.handlers[path][http.MethodGet])
-		m.handlers[path][http.MethodPut] = printDecorator(m.handlers[path][http.MethodPut])
-		m.handlers[path][http.MethodPost] = printDecorator(m.handlers[path][http.MethodPost])
-		m.handlers[path][http.MethodDelete] = printDecorator(m.handlers[path][http.MethodDelete])
-		m.handlers[path][http.MethodOptions] = printDecorator(m.handlers[path][http.MethodOptions])
-	}
-
-}
-
-// GET alias for RegisterHandler w/ GET argument
-func (m *MyMux) GET(path string, h http.HandlerFunc) {
-	m.RegisterHandler(http.MethodGet, path, h)
-}
-
-// PUT alias for RegisterHandler w/ PUT argument
-func (m *MyMux) PUT(path string, h http.HandlerFunc) {
-	m.RegisterHandler(http.MethodPut, path, h)
-}
-
-// POST alias for RegisterHandler w/ POST argument
-func (m *MyMux) POST(path string, h http.HandlerFunc) {
-	m.RegisterHandler(http.MethodPost, path, h)
-}
-
 // printDecorator just prints the request to stdout
 func printDecorator(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {